@@ -1,15 +1,21 @@
 package policy
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
+	"strings"
+	"sync"
 )
 
 // PolicySpec is a specification for a policy.
 type PolicySpec struct {
 	// Role is the role of the user making the request.
 	// "*" matches all roles.
+	//
+	// Role also matches on group membership: a user's group names (see
+	// GroupBinding) are checked against Role alongside their roles, with no
+	// RoleBinding required. A policy scoped with Role: "finance-readers"
+	// is therefore satisfied by any user in a group literally named
+	// "finance-readers", which can be a surprise when authoring
+	// role-scoped policies in a system that also manages groups.
 	Role string `json:"role"`
 	// User is the user-id this rule applies to.
 	// Either user or group is required to match the request.
@@ -20,16 +26,34 @@ type PolicySpec struct {
 	// "*" matches all groups.
 	Group string `json:"group"`
 	// Resource is the name of a resource. Resource, and Namespace are required to match resource requests.
-	// "*" matches all resources
+	// "*" matches all resources. A resource may include a subresource,
+	// e.g. "pods/log", "*/status", or "deployments/*", matched
+	// segment-by-segment by resourceMatches.
 	Resource string `json:"resource"`
 	// Namespace is the name of a namespace. APIGroup, Resource, and Namespace are required to match resource requests.
 	// "*" matches all namespaces (including unnamespaced requests)
 	Namespace string `json:"namespace"`
-	// Readonly matches readonly requests when true, and all requests when false
+	// Readonly matches readonly requests when true, and non-readonly requests
+	// when false, for requests that leave ResourceAttributes.Verb unset.
+	// Deprecated: set Verbs instead. ReadOnly policies are translated into
+	// Verbs at load time ([]string{"get", "list", "watch"} when true,
+	// []string{"*"} when false) and are still honored when a request's
+	// ResourceAttributes.Verb is left unset.
 	ReadOnly bool `json:"readonly"`
-	// NonResourcePath matches non-resource request paths.
-	// "*" matches all paths
-	// "/foo/*" matches all subpaths of foo
+	// Verbs is the list of request verbs this policy allows, e.g. "get",
+	// "list", "watch", "create", "update", "delete". "*" matches all verbs.
+	Verbs []string `json:"verbs"`
+	// Effect determines whether a matching policy allows or denies the
+	// request: "allow" (the default) or "deny". Conflicting matches across
+	// policies are resolved by the PolicyManager's ConflictResolution mode.
+	Effect string `json:"effect"`
+	// NonResourcePath matches non-resource request paths, and only applies
+	// to non-resource requests (see ResourceAttributes.IsNonResourceRequest);
+	// a policy with NonResourcePath set never matches a resource request,
+	// and a policy without it never matches a non-resource request.
+	// "*" matches all paths.
+	// "/foo/*" matches all subpaths of foo, e.g. "/foo/bar" and "/foo/bar/baz".
+	// "/api/*/status" matches segment-by-segment, e.g. "/api/v1/status".
 	NonResourcePath string `json:"nonResourcePath"`
 }
 
@@ -45,93 +69,257 @@ type UserAttributes struct {
 
 // ResourceAttributes holds resource-related attributes.
 type ResourceAttributes struct {
-	// Resource is the name of a resource.
+	// Resource is the name of a resource, optionally including a
+	// subresource, e.g. "pods" or "pods/log".
 	Resource string `json:"resource"`
 	// Namespace is the name of a namespace.
 	Namespace string `json:"namespace"`
 	// ReadOnly is true for read-only requests.
+	// Deprecated: set Verb instead.
 	ReadOnly bool `json:"readOnly"`
+	// Verb is the request verb being performed, e.g. "get", "list",
+	// "watch", "create", "update", "delete".
+	Verb string `json:"verb"`
+	// IsNonResourceRequest is true if the request is for a non-resource
+	// path such as "/healthz" (matched against NonResourcePath) and false
+	// (the default) for an ordinary API resource request (matched against
+	// Resource/Namespace/Verb), mirroring Kubernetes' authorizer.Attributes
+	// while keeping the zero value safe: a ResourceAttributes built without
+	// setting this field is treated as a resource request, not granted
+	// blanket access via a non-resource wildcard policy.
+	IsNonResourceRequest bool `json:"isNonResourceRequest"`
+	// NonResourcePath is the request path for a non-resource request,
+	// e.g. "/healthz" or "/api/v1/status". Only meaningful when
+	// IsNonResourceRequest is true.
+	NonResourcePath string `json:"nonResourcePath"`
 }
 
 // WithDefaultPolicies adds default policies to the PolicyManager.
 func WithDefaultPolicies() PolicyOption {
-	return func(p *PolicyManager) {
-		p.policies = append(p.policies, defaultPolicies()...)
+	return func(p *PolicyManager) error {
+		p.addPolicies(defaultPolicies())
+		return nil
 	}
 }
 
 // WithPolicies adds additional policies to the PolicyManager.
 func WithPolicies(policies ...PolicySpec) PolicyOption {
-	return func(p *PolicyManager) {
-		p.policies = append(p.policies, policies...)
+	return func(p *PolicyManager) error {
+		p.addPolicies(policies)
+		return nil
 	}
 }
 
-// WithPoliciesFromFile loads policies from a JSON file and adds them to the PolicyManager.
+// WithPoliciesFromFile loads policies from a JSON file and adds them to
+// the PolicyManager. It is a thin convenience wrapper around WithAdapter
+// and NewFileAdapter.
 func WithPoliciesFromFile(filename string) PolicyOption {
-	return func(p *PolicyManager) {
-		file, err := os.Open(filename)
-		if err != nil {
-			panic(fmt.Sprintf("failed to open policies file: %v", err))
-		}
-		defer file.Close()
-
-		var newPolicies []PolicySpec
-		if err := json.NewDecoder(file).Decode(&newPolicies); err != nil {
-			panic(fmt.Sprintf("failed to decode policies from file: %v\n", err))
-		}
+	return WithAdapter(NewFileAdapter(filename))
+}
 
-		p.policies = append(p.policies, newPolicies...)
-	}
+// addPolicies normalizes and appends policies to the manager under lock.
+func (p *PolicyManager) addPolicies(policies []PolicySpec) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policies = append(p.policies, normalizePolicies(policies)...)
 }
 
-// defaultPolicies returns the default set of policies.
+// defaultPolicies returns the default set of policies: read-only access to
+// every resource for any role, full access for admins, and unauthenticated
+// access to non-resource paths such as health checks.
 func defaultPolicies() []PolicySpec {
-	// Return an empty slice or define default policies here.
 	return []PolicySpec{
-		{Role: "*", User: "*", Group: "*", Resource: "*", Namespace: "*", ReadOnly: true, NonResourcePath: "*"},
-		{Role: "admin", User: "*", Group: "*", Resource: "*", Namespace: "*", ReadOnly: false, NonResourcePath: "*"},
+		{Role: "*", User: "*", Group: "*", Resource: "*", Namespace: "*", ReadOnly: true, Verbs: []string{"get", "list", "watch"}, Effect: "allow"},
+		{Role: "admin", User: "*", Group: "*", Resource: "*", Namespace: "*", ReadOnly: false, Verbs: []string{"*"}, Effect: "allow"},
+		{Role: "*", User: "*", Group: "*", Effect: "allow", NonResourcePath: "*"},
+	}
+}
+
+// normalizePolicies returns a copy of policies with Verbs populated from
+// the legacy ReadOnly flag and Effect defaulted to "allow", for any policy
+// that doesn't already set them, so downstream matching can rely on Verbs
+// and Effect alone.
+func normalizePolicies(policies []PolicySpec) []PolicySpec {
+	normalized := make([]PolicySpec, len(policies))
+	for i, policy := range policies {
+		if len(policy.Verbs) == 0 {
+			if policy.ReadOnly {
+				policy.Verbs = []string{"get", "list", "watch"}
+			} else {
+				policy.Verbs = []string{"*"}
+			}
+		}
+		if policy.Effect == "" {
+			policy.Effect = "allow"
+		}
+		normalized[i] = policy
 	}
+	return normalized
 }
 
 // PolicyManager is an ABAC policy engine.
 //
 // We use ABAC (Attribute-Based Access Control) to define policies.
 type PolicyManager struct {
+	mu       sync.RWMutex
 	policies []PolicySpec
+	// model holds an optional PERM-style model loaded via WithModel,
+	// enabling the Enforce method alongside the default Evaluate path.
+	model *Model
+	// roleManager resolves role/group inheritance configured via
+	// WithRoleHierarchy and WithGroupBindings. Always set by
+	// NewPolicyManagerWithError so roles() can return it without locking.
+	roleManager *RoleManager
+	// conflictResolution determines how EvaluateDetailed resolves multiple
+	// matching policies with different effects. Defaults to DenyOverrides.
+	conflictResolution ConflictResolution
+	// adapter is the PolicyAdapter configured via WithAdapter, used by
+	// StartWatch to hot-reload policies.
+	adapter PolicyAdapter
 }
 
-// PolicyOption defines a function that applies a configuration to the PolicyManager.
-type PolicyOption func(*PolicyManager)
+// PolicyOption defines a function that applies a configuration to the
+// PolicyManager. It returns an error so options that load from external
+// sources (files, models, adapters) can report failures instead of
+// panicking; see NewPolicyManagerWithError.
+type PolicyOption func(*PolicyManager) error
 
-// NewPolicyManager initializes a new policy manager with optional policies.
+// NewPolicyManager initializes a new policy manager with optional
+// policies. It panics if any option fails; use NewPolicyManagerWithError
+// to handle option failures (e.g. a missing policies file) explicitly.
 func NewPolicyManager(opts ...PolicyOption) *PolicyManager {
-	p := &PolicyManager{}
-	for _, opt := range opts {
-		opt(p)
+	p, err := NewPolicyManagerWithError(opts...)
+	if err != nil {
+		panic(err)
 	}
 	return p
 }
 
-// Evaluate checks if any policy allows the action based on user and resource attributes.
+// NewPolicyManagerWithError initializes a new policy manager with optional
+// policies, returning an error if any option fails instead of panicking.
+func NewPolicyManagerWithError(opts ...PolicyOption) (*PolicyManager, error) {
+	p := &PolicyManager{roleManager: newRoleManager()}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Evaluate checks if any policy allows the action based on user and
+// resource attributes. It is a convenience wrapper around EvaluateDetailed
+// for callers that don't need to know which policy decided the outcome.
 func (p *PolicyManager) Evaluate(user *UserAttributes, resource *ResourceAttributes) bool {
-	for _, policy := range p.policies {
-		if p.matchPolicy(policy, user, resource) {
+	return p.EvaluateDetailed(user, resource).Allowed
+}
+
+// matchPolicy checks if a policy matches the provided user and resource
+// attributes. Note that the user's groups are expanded alongside their
+// roles through the same role hierarchy (see GroupBinding), so a policy's
+// Role field can match on group membership too.
+func (p *PolicyManager) matchPolicy(policy PolicySpec, user *UserAttributes, resource *ResourceAttributes) bool {
+	roles := p.expandRoles(append(append([]string{}, user.Roles...), p.roles().groupsFor(user.UserID)...))
+	groups := p.roles().groupsFor(user.UserID)
+	if user.GroupID != "" {
+		groups = append(groups, user.GroupID)
+	}
+
+	if !matchesSlice(policy.Role, roles) ||
+		!matchesString(policy.User, user.UserID) ||
+		!matchesSlice(policy.Group, groups) ||
+		!matchesVerb(policy, resource) {
+		return false
+	}
+
+	// A non-resource policy (NonResourcePath set) only matches non-resource
+	// requests, and vice versa.
+	if !resource.IsNonResourceRequest {
+		if policy.NonResourcePath != "" {
+			return false
+		}
+		return resourceMatches(policy.Resource, resource.Resource) &&
+			matchesString(policy.Namespace, resource.Namespace)
+	}
+
+	if policy.NonResourcePath == "" {
+		return false
+	}
+	return nonResourcePathMatches(policy.NonResourcePath, resource.NonResourcePath)
+}
+
+// nonResourcePathMatches checks a non-resource path pattern against a
+// requested path, matching segment-by-segment ("/"-separated): "*" matches
+// any path, a trailing "*" segment matches the rest of the path (e.g.
+// "/foo/*" matches "/foo/bar" and "/foo/bar/baz"), and a "*" segment
+// anywhere else matches exactly one path segment (e.g. "/api/*/status"
+// matches "/api/v1/status").
+func nonResourcePathMatches(pattern, path string) bool {
+	if pattern == "*" || pattern == "" {
+		return true
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+
+	for i, seg := range patternSegments {
+		if seg == "*" && i == len(patternSegments)-1 {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if seg != "*" && seg != pathSegments[i] {
+			return false
+		}
+	}
+	return len(pathSegments) == len(patternSegments)
+}
+
+// matchesVerb checks whether a policy permits the request's verb. When the
+// caller hasn't set ResourceAttributes.Verb, it falls back to the legacy
+// ReadOnly comparison so existing callers keep working unmodified.
+func matchesVerb(policy PolicySpec, resource *ResourceAttributes) bool {
+	if resource.Verb == "" {
+		return policy.ReadOnly == resource.ReadOnly
+	}
+	return verbAllowed(policy.Verbs, resource.Verb)
+}
+
+// verbAllowed reports whether verb is permitted by verbs, treating an empty
+// or "*"-containing verb list as permitting anything.
+func verbAllowed(verbs []string, verb string) bool {
+	if len(verbs) == 0 {
+		return true
+	}
+	for _, v := range verbs {
+		if v == "*" || v == verb {
 			return true
 		}
 	}
 	return false
 }
 
-// matchPolicy checks if a policy matches the provided user and resource attributes.
-func (p *PolicyManager) matchPolicy(policy PolicySpec, user *UserAttributes, resource *ResourceAttributes) bool {
-	return matchesSlice(policy.Role, user.Roles) &&
-		matchesString(policy.User, user.UserID) &&
-		matchesString(policy.Group, user.GroupID) &&
-		matchesString(policy.Resource, resource.Resource) &&
-		matchesString(policy.Namespace, resource.Namespace) &&
-		matchesString(policy.NonResourcePath, resource.Resource) &&
-		(policy.ReadOnly == resource.ReadOnly)
+// resourceMatches checks a resource pattern (optionally "resource/subresource",
+// e.g. "pods/log", "*/status", or "deployments/*") against a requested
+// resource, matching each "/"-separated segment independently so a
+// wildcard in one segment doesn't also match a differing segment count.
+func resourceMatches(pattern, resource string) bool {
+	if pattern == "*" || pattern == "" {
+		return true
+	}
+	patternSegments := strings.Split(pattern, "/")
+	resourceSegments := strings.Split(resource, "/")
+	if len(patternSegments) != len(resourceSegments) {
+		return false
+	}
+	for i, seg := range patternSegments {
+		if seg == "*" || seg == resourceSegments[i] {
+			continue
+		}
+		return false
+	}
+	return true
 }
 
 // matchesSlice checks if a value is in a list or matches a wildcard "*".
@@ -75,3 +75,139 @@ func TestPolicyManager_Evaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyManager_Evaluate_Verbs(t *testing.T) {
+	testCases := []struct {
+		name               string
+		policies           []policy.PolicySpec
+		resourceAttributes *policy.ResourceAttributes
+		expectedResult     bool
+	}{
+		{
+			name: "Allowed verb matches",
+			policies: []policy.PolicySpec{
+				{Role: "*", Resource: "pods", Verbs: []string{"get", "list"}},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "pods", Verb: "get"},
+			expectedResult:     true,
+		},
+		{
+			name: "Disallowed verb does not match",
+			policies: []policy.PolicySpec{
+				{Role: "*", Resource: "pods", Verbs: []string{"get", "list"}},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "pods", Verb: "delete"},
+			expectedResult:     false,
+		},
+		{
+			name: "Subresource glob matches",
+			policies: []policy.PolicySpec{
+				{Role: "*", Resource: "*/status", Verbs: []string{"update"}},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "deployments/status", Verb: "update"},
+			expectedResult:     true,
+		},
+		{
+			name: "Plain resource does not match its subresource (differing segment count)",
+			policies: []policy.PolicySpec{
+				{Role: "*", Resource: "pods", Verbs: []string{"*"}},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "pods/log", Verb: "get"},
+			expectedResult:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pm := policy.NewPolicyManager(policy.WithPolicies(tc.policies...))
+			result := pm.Evaluate(&policy.UserAttributes{Roles: []string{"anyone"}}, tc.resourceAttributes)
+			if result != tc.expectedResult {
+				t.Errorf("Expected %v, but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestPolicyManager_Evaluate_NonResourcePaths(t *testing.T) {
+	testCases := []struct {
+		name               string
+		policies           []policy.PolicySpec
+		resourceAttributes *policy.ResourceAttributes
+		expectedResult     bool
+	}{
+		{
+			name: "Exact path match",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "/healthz"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/healthz"},
+			expectedResult:     true,
+		},
+		{
+			name: "Trailing wildcard matches subpaths",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "/api/v1/*"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/api/v1/status"},
+			expectedResult:     true,
+		},
+		{
+			name: "Segment wildcard matches one segment",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "/api/*/status"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/api/v1/status"},
+			expectedResult:     true,
+		},
+		{
+			name: "Segment wildcard does not match extra segments",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "/api/*/status"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/api/v1/beta/status"},
+			expectedResult:     false,
+		},
+		{
+			name: "Trailing slash does not match a policy for the bare path",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "/healthz"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/healthz/"},
+			expectedResult:     false,
+		},
+		{
+			name: "Resource policy does not match a non-resource request",
+			policies: []policy.PolicySpec{
+				{Role: "*", Resource: "*", Verbs: []string{"*"}},
+			},
+			resourceAttributes: &policy.ResourceAttributes{IsNonResourceRequest: true, NonResourcePath: "/healthz"},
+			expectedResult:     false,
+		},
+		{
+			name: "Non-resource policy does not match a resource request",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "*"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "pods", Verb: "get"},
+			expectedResult:     false,
+		},
+		{
+			name: "Default zero-value attributes are not granted by a non-resource wildcard",
+			policies: []policy.PolicySpec{
+				{Role: "*", NonResourcePath: "*"},
+			},
+			resourceAttributes: &policy.ResourceAttributes{Resource: "secrets"},
+			expectedResult:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pm := policy.NewPolicyManager(policy.WithPolicies(tc.policies...))
+			result := pm.Evaluate(&policy.UserAttributes{Roles: []string{"anyone"}}, tc.resourceAttributes)
+			if result != tc.expectedResult {
+				t.Errorf("Expected %v, but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
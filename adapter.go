@@ -0,0 +1,367 @@
+package policy
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// PolicyAdapter loads and persists policies from an external source, and
+// optionally watches that source for changes. WithPoliciesFromFile and the
+// New*Adapter constructors are the built-in implementations; callers can
+// supply their own to back policies with any store.
+type PolicyAdapter interface {
+	// Load reads the full set of policies from the underlying store.
+	Load() ([]PolicySpec, error)
+	// Save persists the full set of policies to the underlying store.
+	Save(policies []PolicySpec) error
+	// Watch returns a channel that emits the full policy set whenever the
+	// underlying store changes, and is closed when ctx is done.
+	Watch(ctx context.Context) (<-chan []PolicySpec, error)
+}
+
+// pollInterval is how often watch-via-polling adapters re-check their
+// source for changes, for stores with no native change notification.
+const pollInterval = 5 * time.Second
+
+// pollAdapter emits adapter's current policies immediately, then polls
+// Load on pollInterval and emits again whenever the result changes. It is
+// shared by every built-in adapter's Watch implementation.
+func pollAdapter(ctx context.Context, adapter PolicyAdapter) (<-chan []PolicySpec, error) {
+	initial, err := adapter.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan []PolicySpec, 1)
+	ch <- initial
+
+	go func() {
+		defer close(ch)
+		last := initial
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := adapter.Load()
+				if err != nil {
+					continue
+				}
+				if !policiesEqual(last, current) {
+					last = current
+					ch <- current
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// policiesEqual reports whether a and b contain the same policies in the
+// same order.
+func policiesEqual(a, b []PolicySpec) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aj) == string(bj)
+}
+
+// FileAdapter loads and saves policies as a single JSON array file.
+type FileAdapter struct {
+	Path string
+}
+
+// NewFileAdapter returns a PolicyAdapter backed by a JSON array file.
+func NewFileAdapter(path string) *FileAdapter {
+	return &FileAdapter{Path: path}
+}
+
+// Load implements PolicyAdapter.
+func (a *FileAdapter) Load() ([]PolicySpec, error) {
+	file, err := os.Open(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policies file: %w", err)
+	}
+	defer file.Close()
+
+	var policies []PolicySpec
+	if err := json.NewDecoder(file).Decode(&policies); err != nil {
+		return nil, fmt.Errorf("failed to decode policies from file: %w", err)
+	}
+	return policies, nil
+}
+
+// Save implements PolicyAdapter.
+func (a *FileAdapter) Save(policies []PolicySpec) error {
+	file, err := os.Create(a.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create policies file: %w", err)
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(policies); err != nil {
+		return fmt.Errorf("failed to encode policies to file: %w", err)
+	}
+	return nil
+}
+
+// Watch implements PolicyAdapter by polling the file for changes.
+func (a *FileAdapter) Watch(ctx context.Context) (<-chan []PolicySpec, error) {
+	return pollAdapter(ctx, a)
+}
+
+// JSONLAdapter loads and saves policies as newline-delimited JSON, one
+// policy object per line, in the style of Kubernetes' legacy ABAC policy
+// files.
+type JSONLAdapter struct {
+	Path string
+}
+
+// NewJSONLAdapter returns a PolicyAdapter backed by a line-delimited JSON
+// file.
+func NewJSONLAdapter(path string) *JSONLAdapter {
+	return &JSONLAdapter{Path: path}
+}
+
+// Load implements PolicyAdapter.
+func (a *JSONLAdapter) Load() ([]PolicySpec, error) {
+	file, err := os.Open(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policies file: %w", err)
+	}
+	defer file.Close()
+
+	var policies []PolicySpec
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var spec PolicySpec
+		if err := json.Unmarshal([]byte(line), &spec); err != nil {
+			return nil, fmt.Errorf("failed to decode policy line: %w", err)
+		}
+		policies = append(policies, spec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read policies file: %w", err)
+	}
+	return policies, nil
+}
+
+// Save implements PolicyAdapter.
+func (a *JSONLAdapter) Save(policies []PolicySpec) error {
+	file, err := os.Create(a.Path)
+	if err != nil {
+		return fmt.Errorf("failed to create policies file: %w", err)
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	for _, spec := range policies {
+		if err := enc.Encode(spec); err != nil {
+			return fmt.Errorf("failed to encode policy line: %w", err)
+		}
+	}
+	return nil
+}
+
+// Watch implements PolicyAdapter by polling the file for changes.
+func (a *JSONLAdapter) Watch(ctx context.Context) (<-chan []PolicySpec, error) {
+	return pollAdapter(ctx, a)
+}
+
+// HTTPAdapter loads policies as a JSON array from a remote URL. Save is
+// unsupported since there is no generic protocol for pushing policy
+// updates over plain HTTP.
+type HTTPAdapter struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// NewHTTPAdapter returns a PolicyAdapter that fetches a JSON array of
+// policies from url, sending the given headers with every request.
+func NewHTTPAdapter(url string, headers map[string]string) *HTTPAdapter {
+	return &HTTPAdapter{URL: url, Headers: headers, Client: http.DefaultClient}
+}
+
+// Load implements PolicyAdapter.
+func (a *HTTPAdapter) Load() ([]PolicySpec, error) {
+	req, err := http.NewRequest(http.MethodGet, a.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range a.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policies: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch policies: status %d: %s", resp.StatusCode, body)
+	}
+
+	var policies []PolicySpec
+	if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+		return nil, fmt.Errorf("failed to decode policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Save implements PolicyAdapter. HTTPAdapter is read-only.
+func (a *HTTPAdapter) Save([]PolicySpec) error {
+	return fmt.Errorf("policy: HTTPAdapter does not support Save")
+}
+
+// Watch implements PolicyAdapter by polling the URL for changes.
+func (a *HTTPAdapter) Watch(ctx context.Context) (<-chan []PolicySpec, error) {
+	return pollAdapter(ctx, a)
+}
+
+// SQLAdapter loads and saves policies via database/sql. The table is
+// expected to have columns matching PolicySpec: role, user, "group",
+// resource, namespace, readonly, verbs (comma-separated), effect, and
+// non_resource_path.
+type SQLAdapter struct {
+	DB    *sql.DB
+	Table string
+}
+
+// NewSQLAdapter returns a PolicyAdapter backed by the given table.
+func NewSQLAdapter(db *sql.DB, table string) *SQLAdapter {
+	return &SQLAdapter{DB: db, Table: table}
+}
+
+// Load implements PolicyAdapter.
+func (a *SQLAdapter) Load() ([]PolicySpec, error) {
+	query := fmt.Sprintf(
+		`SELECT role, "user", "group", resource, namespace, readonly, verbs, effect, non_resource_path FROM %s`,
+		a.Table,
+	)
+	rows, err := a.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []PolicySpec
+	for rows.Next() {
+		var spec PolicySpec
+		var verbs string
+		if err := rows.Scan(&spec.Role, &spec.User, &spec.Group, &spec.Resource, &spec.Namespace, &spec.ReadOnly, &verbs, &spec.Effect, &spec.NonResourcePath); err != nil {
+			return nil, fmt.Errorf("failed to scan policy row: %w", err)
+		}
+		if verbs != "" {
+			spec.Verbs = strings.Split(verbs, ",")
+		}
+		policies = append(policies, spec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read policy rows: %w", err)
+	}
+	return policies, nil
+}
+
+// Save implements PolicyAdapter, replacing the table's full contents.
+func (a *SQLAdapter) Save(policies []PolicySpec) error {
+	tx, err := a.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", a.Table)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear policies table: %w", err)
+	}
+
+	insert := fmt.Sprintf(
+		`INSERT INTO %s (role, "user", "group", resource, namespace, readonly, verbs, effect, non_resource_path) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Table,
+	)
+	for _, spec := range policies {
+		_, err := tx.Exec(insert, spec.Role, spec.User, spec.Group, spec.Resource, spec.Namespace, spec.ReadOnly, strings.Join(spec.Verbs, ","), spec.Effect, spec.NonResourcePath)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert policy: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Watch implements PolicyAdapter by polling the table for changes.
+func (a *SQLAdapter) Watch(ctx context.Context) (<-chan []PolicySpec, error) {
+	return pollAdapter(ctx, a)
+}
+
+// WithAdapter configures adapter as the PolicyManager's policy source,
+// loading its current policies immediately. Combine with StartWatch to
+// hot-reload policies as the adapter's underlying store changes.
+func WithAdapter(adapter PolicyAdapter) PolicyOption {
+	return func(p *PolicyManager) error {
+		policies, err := adapter.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load policies from adapter: %w", err)
+		}
+		p.adapter = adapter
+		p.addPolicies(policies)
+		return nil
+	}
+}
+
+// StartWatch begins watching the PolicyManager's configured adapter (set
+// via WithAdapter) for changes, replacing the in-memory policy set with
+// each update it emits until ctx is done. It returns an error immediately
+// if no adapter is configured.
+func (p *PolicyManager) StartWatch(ctx context.Context) error {
+	if p.adapter == nil {
+		return fmt.Errorf("policy: no adapter configured, use WithAdapter")
+	}
+
+	updates, err := p.adapter.Watch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start watch: %w", err)
+	}
+
+	go func() {
+		for policies := range updates {
+			p.mu.Lock()
+			p.policies = normalizePolicies(policies)
+			p.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
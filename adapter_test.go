@@ -0,0 +1,89 @@
+package policy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cvcio/policy"
+)
+
+func TestFileAdapter_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	adapter := policy.NewFileAdapter(path)
+
+	want := []policy.PolicySpec{
+		{Role: "admin", Resource: "*", Verbs: []string{"*"}, Effect: "allow"},
+	}
+	if err := adapter.Save(want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := adapter.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Role != "admin" {
+		t.Fatalf("expected the saved policy back, got %+v", got)
+	}
+}
+
+func TestJSONLAdapter_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.jsonl")
+	adapter := policy.NewJSONLAdapter(path)
+
+	want := []policy.PolicySpec{
+		{Role: "viewer", Resource: "documents", ReadOnly: true},
+		{Role: "editor", Resource: "documents", ReadOnly: false},
+	}
+	if err := adapter.Save(want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := adapter.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(got))
+	}
+}
+
+func TestPolicyManager_WithAdapter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(`[{"role":"admin","resource":"*","readonly":false}]`), 0o644); err != nil {
+		t.Fatalf("failed to write policies file: %v", err)
+	}
+
+	pm, err := policy.NewPolicyManagerWithError(policy.WithAdapter(policy.NewFileAdapter(path)))
+	if err != nil {
+		t.Fatalf("NewPolicyManagerWithError failed: %v", err)
+	}
+
+	result := pm.Evaluate(
+		&policy.UserAttributes{Roles: []string{"admin"}},
+		&policy.ResourceAttributes{Resource: "anything", ReadOnly: false},
+	)
+	if !result {
+		t.Error("expected the policy loaded from the adapter to allow the request")
+	}
+}
+
+func TestPolicyManager_WithAdapter_MissingFile(t *testing.T) {
+	_, err := policy.NewPolicyManagerWithError(policy.WithAdapter(policy.NewFileAdapter("/nonexistent/policies.json")))
+	if err == nil {
+		t.Fatal("expected an error for a missing policies file")
+	}
+}
+
+func TestPolicyManager_StartWatch_NoAdapter(t *testing.T) {
+	pm := policy.NewPolicyManager()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := pm.StartWatch(ctx); err == nil {
+		t.Fatal("expected an error when no adapter is configured")
+	}
+}
@@ -0,0 +1,83 @@
+package policy
+
+// ConflictResolution selects how EvaluateDetailed resolves a request that
+// matches multiple policies with different effects.
+type ConflictResolution int
+
+const (
+	// DenyOverrides denies the request if any matching policy denies it,
+	// regardless of how many allow it. This is the default.
+	DenyOverrides ConflictResolution = iota
+	// AllowOverrides allows the request if any matching policy allows it,
+	// regardless of how many deny it.
+	AllowOverrides
+	// PriorityOrder honors the effect of the first matching policy, in the
+	// order policies were added to the PolicyManager.
+	PriorityOrder
+)
+
+// WithConflictResolution sets how the PolicyManager resolves a request
+// matched by policies with conflicting effects. Defaults to DenyOverrides.
+func WithConflictResolution(mode ConflictResolution) PolicyOption {
+	return func(p *PolicyManager) error {
+		p.conflictResolution = mode
+		return nil
+	}
+}
+
+// Decision is the result of EvaluateDetailed, recording not just whether a
+// request was allowed but which policy decided it and why.
+type Decision struct {
+	// Allowed is true if the request was permitted.
+	Allowed bool
+	// MatchedPolicy is the policy that decided the outcome, or nil if no
+	// policy matched the request at all.
+	MatchedPolicy *PolicySpec
+	// Reason is a human-readable explanation of the decision.
+	Reason string
+}
+
+// EvaluateDetailed checks user and resource attributes against every
+// policy and returns a Decision explaining the outcome. Unlike the boolean
+// Evaluate, it surfaces which policy matched and how conflicts between
+// allow and deny policies were resolved, per the PolicyManager's
+// ConflictResolution mode.
+func (p *PolicyManager) EvaluateDetailed(user *UserAttributes, resource *ResourceAttributes) Decision {
+	p.mu.RLock()
+	policies := make([]PolicySpec, len(p.policies))
+	copy(policies, p.policies)
+	p.mu.RUnlock()
+
+	var matched []PolicySpec
+	for _, policy := range policies {
+		if p.matchPolicy(policy, user, resource) {
+			matched = append(matched, policy)
+		}
+	}
+	if len(matched) == 0 {
+		return Decision{Allowed: false, Reason: "no matching policy"}
+	}
+
+	switch p.conflictResolution {
+	case AllowOverrides:
+		for i := range matched {
+			if matched[i].Effect == "allow" {
+				return Decision{Allowed: true, MatchedPolicy: &matched[i], Reason: "allow-overrides: matched an allow policy"}
+			}
+		}
+		return Decision{Allowed: false, MatchedPolicy: &matched[len(matched)-1], Reason: "allow-overrides: only deny policies matched"}
+	case PriorityOrder:
+		first := &matched[0]
+		if first.Effect == "deny" {
+			return Decision{Allowed: false, MatchedPolicy: first, Reason: "priority-order: first matching policy denies"}
+		}
+		return Decision{Allowed: true, MatchedPolicy: first, Reason: "priority-order: first matching policy allows"}
+	default: // DenyOverrides
+		for i := range matched {
+			if matched[i].Effect == "deny" {
+				return Decision{Allowed: false, MatchedPolicy: &matched[i], Reason: "deny-overrides: matched a deny policy"}
+			}
+		}
+		return Decision{Allowed: true, MatchedPolicy: &matched[0], Reason: "deny-overrides: only allow policies matched"}
+	}
+}
@@ -0,0 +1,99 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cvcio/policy"
+)
+
+func TestPolicyManager_EvaluateDetailed_DenyOverrides(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithPolicies(
+			policy.PolicySpec{Role: "*", Resource: "secrets", Verbs: []string{"*"}, Effect: "allow"},
+			policy.PolicySpec{Role: "intern", Resource: "secrets", Verbs: []string{"*"}, Effect: "deny"},
+		),
+	)
+
+	decision := pm.EvaluateDetailed(
+		&policy.UserAttributes{Roles: []string{"intern"}},
+		&policy.ResourceAttributes{Resource: "secrets", Verb: "get"},
+	)
+	if decision.Allowed {
+		t.Errorf("expected deny-overrides to reject the request, got %+v", decision)
+	}
+	if decision.MatchedPolicy == nil || decision.MatchedPolicy.Effect != "deny" {
+		t.Errorf("expected the deny policy to be reported as the match, got %+v", decision.MatchedPolicy)
+	}
+}
+
+func TestPolicyManager_EvaluateDetailed_AllowOverrides(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithConflictResolution(policy.AllowOverrides),
+		policy.WithPolicies(
+			policy.PolicySpec{Role: "*", Resource: "secrets", Verbs: []string{"*"}, Effect: "deny"},
+			policy.PolicySpec{Role: "admin", Resource: "secrets", Verbs: []string{"*"}, Effect: "allow"},
+		),
+	)
+
+	decision := pm.EvaluateDetailed(
+		&policy.UserAttributes{Roles: []string{"admin"}},
+		&policy.ResourceAttributes{Resource: "secrets", Verb: "get"},
+	)
+	if !decision.Allowed {
+		t.Errorf("expected allow-overrides to permit the request, got %+v", decision)
+	}
+}
+
+func TestPolicyManager_EvaluateDetailed_PriorityOrder(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithConflictResolution(policy.PriorityOrder),
+		policy.WithPolicies(
+			policy.PolicySpec{Role: "admin", Resource: "secrets", Verbs: []string{"*"}, Effect: "allow"},
+			policy.PolicySpec{Role: "*", Resource: "secrets", Verbs: []string{"*"}, Effect: "deny"},
+		),
+	)
+
+	decision := pm.EvaluateDetailed(
+		&policy.UserAttributes{Roles: []string{"admin"}},
+		&policy.ResourceAttributes{Resource: "secrets", Verb: "get"},
+	)
+	if !decision.Allowed {
+		t.Errorf("expected priority-order to honor the first matching (allow) policy, got %+v", decision)
+	}
+	if decision.MatchedPolicy == nil || decision.MatchedPolicy.Effect != "allow" {
+		t.Errorf("expected the allow policy to be reported as the match, got %+v", decision.MatchedPolicy)
+	}
+}
+
+func TestPolicyManager_EvaluateDetailed_PriorityOrder_FirstDenyWins(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithConflictResolution(policy.PriorityOrder),
+		policy.WithPolicies(
+			policy.PolicySpec{Role: "*", Resource: "secrets", Verbs: []string{"*"}, Effect: "deny"},
+			policy.PolicySpec{Role: "admin", Resource: "secrets", Verbs: []string{"*"}, Effect: "allow"},
+		),
+	)
+
+	decision := pm.EvaluateDetailed(
+		&policy.UserAttributes{Roles: []string{"admin"}},
+		&policy.ResourceAttributes{Resource: "secrets", Verb: "get"},
+	)
+	if decision.Allowed {
+		t.Errorf("expected priority-order to honor the first matching (deny) policy, got %+v", decision)
+	}
+	if decision.MatchedPolicy == nil || decision.MatchedPolicy.Effect != "deny" {
+		t.Errorf("expected the deny policy to be reported as the match, got %+v", decision.MatchedPolicy)
+	}
+}
+
+func TestPolicyManager_EvaluateDetailed_NoMatch(t *testing.T) {
+	pm := policy.NewPolicyManager()
+
+	decision := pm.EvaluateDetailed(
+		&policy.UserAttributes{UserID: "nobody"},
+		&policy.ResourceAttributes{Resource: "secrets", Verb: "get"},
+	)
+	if decision.Allowed || decision.MatchedPolicy != nil {
+		t.Errorf("expected no policy to match, got %+v", decision)
+	}
+}
@@ -0,0 +1,656 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Model represents a parsed PERM-style policy model, loosely inspired by
+// Casbin's model files (request_definition, policy_definition,
+// policy_effect, matchers). It lets callers describe how requests and
+// policies map onto one another and how conflicting policy rows combine,
+// without pulling in an external dependency.
+type Model struct {
+	// RequestDef holds the ordered token names from [request_definition],
+	// e.g. []string{"r.sub", "r.obj", "r.act"}.
+	RequestDef []string
+	// PolicyDef holds the ordered token names from [policy_definition],
+	// e.g. []string{"p.sub", "p.obj", "p.act", "p.eft"}.
+	PolicyDef []string
+	// PolicyEffect is the raw expression from [policy_effect], e.g.
+	// "some(where (p.eft == allow))".
+	PolicyEffect string
+	// Matchers is the raw expression from [matchers].
+	Matchers string
+}
+
+// LoadModel parses a PERM model file at path. The file uses an INI-like
+// syntax with `[section]` headers and `key = value` assignments, mirroring
+// Casbin's model format:
+//
+//	[request_definition]
+//	r = sub, obj, act
+//
+//	[policy_definition]
+//	p = sub, obj, act, eft
+//
+//	[policy_effect]
+//	e = some(where (p.eft == allow))
+//
+//	[matchers]
+//	m = r.sub == p.sub && r.obj == p.obj && r.act == p.act
+func LoadModel(path string) (*Model, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open model file: %w", err)
+	}
+	defer file.Close()
+
+	m := &Model{}
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			continue
+		}
+		varName := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+
+		switch section {
+		case "request_definition":
+			m.RequestDef = splitTokens(value, varName)
+		case "policy_definition":
+			m.PolicyDef = splitTokens(value, varName)
+		case "policy_effect":
+			m.PolicyEffect = value
+		case "matchers":
+			m.Matchers = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+	if m.Matchers == "" {
+		return nil, fmt.Errorf("model file %s is missing a [matchers] section", path)
+	}
+	return m, nil
+}
+
+// splitTokens splits a comma-separated token list such as "sub, obj, act"
+// declared by a section's "r = ..." or "p = ..." assignment, qualifying each
+// token with the assignment's variable name so matcher expressions can refer
+// to it as e.g. "r.sub" or "p.eft".
+func splitTokens(value, prefix string) []string {
+	parts := strings.Split(value, ",")
+	tokens := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if t := strings.TrimSpace(p); t != "" {
+			tokens = append(tokens, prefix+"."+t)
+		}
+	}
+	return tokens
+}
+
+// WithModel loads a PERM model file and attaches it to the PolicyManager,
+// enabling Enforce in addition to the existing Evaluate path.
+func WithModel(path string) PolicyOption {
+	return func(p *PolicyManager) error {
+		model, err := LoadModel(path)
+		if err != nil {
+			return fmt.Errorf("failed to load model: %w", err)
+		}
+		p.model = model
+		return nil
+	}
+}
+
+// Enforce evaluates the configured model's matcher expression against each
+// policy row and combines the per-row results using the model's
+// policy_effect expression. Unlike Evaluate, Enforce requires a model to
+// have been set via WithModel.
+//
+// request values are bound positionally to the model's request_definition
+// tokens (r.sub, r.obj, r.act, ...) as strings.
+func (p *PolicyManager) Enforce(request ...interface{}) (bool, error) {
+	if p.model == nil {
+		return false, fmt.Errorf("policy: no model configured, use WithModel")
+	}
+	if len(request) < len(p.model.RequestDef) {
+		return false, fmt.Errorf("policy: expected %d request args, got %d", len(p.model.RequestDef), len(request))
+	}
+
+	matcher, err := compileExpr(p.model.Matchers)
+	if err != nil {
+		return false, fmt.Errorf("failed to compile matcher: %w", err)
+	}
+
+	p.mu.RLock()
+	policies := make([]PolicySpec, len(p.policies))
+	copy(policies, p.policies)
+	p.mu.RUnlock()
+
+	effects := make([]string, 0, len(policies))
+	for _, row := range policies {
+		bindings := make(map[string]interface{}, len(p.model.RequestDef)+len(p.model.PolicyDef))
+		for i, tok := range p.model.RequestDef {
+			bindings[tok] = fmt.Sprintf("%v", request[i])
+		}
+		for _, tok := range p.model.PolicyDef {
+			bindings[tok] = policyFieldValue(tok, row)
+		}
+		eft := policyEft(row)
+
+		ok, err := matcher.Eval(bindings)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate matcher: %w", err)
+		}
+		if truthy(ok) {
+			effects = append(effects, eft)
+		}
+	}
+
+	return combineEffects(p.model.PolicyEffect, effects)
+}
+
+// policyAct derives the "act" token for a policy row from its verbs,
+// falling back to the legacy ReadOnly flag when no verbs are set.
+func policyAct(row PolicySpec) string {
+	if len(row.Verbs) > 0 {
+		return strings.Join(row.Verbs, ",")
+	}
+	if row.ReadOnly {
+		return "read"
+	}
+	return "write"
+}
+
+// policyFieldValue resolves a [policy_definition] token such as "p.sub" or
+// "p.eft" to its value for the given policy row, using the token's suffix
+// (the part after the last ".") to pick the right PolicySpec field. Unknown
+// suffixes resolve to the empty string.
+func policyFieldValue(tok string, row PolicySpec) string {
+	suffix := tok
+	if idx := strings.LastIndex(tok, "."); idx >= 0 {
+		suffix = tok[idx+1:]
+	}
+	switch suffix {
+	case "sub":
+		return policySub(row)
+	case "obj":
+		return row.Resource
+	case "act":
+		return policyAct(row)
+	case "eft":
+		return policyEft(row)
+	default:
+		return ""
+	}
+}
+
+// policyEft derives the "eft" token for a policy row from its Effect
+// field, defaulting to "allow" for policies that don't set one.
+func policyEft(row PolicySpec) string {
+	if row.Effect != "" {
+		return row.Effect
+	}
+	return "allow"
+}
+
+// policySub derives the "sub" token for a policy row, preferring whichever
+// of User or Role is the more specific (non-wildcard) match.
+func policySub(row PolicySpec) string {
+	if row.User != "" && row.User != "*" {
+		return row.User
+	}
+	if row.Role != "" && row.Role != "*" {
+		return row.Role
+	}
+	return "*"
+}
+
+// truthy converts an evaluator result into a bool.
+func truthy(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// combineEffects implements the small set of policy_effect combinators
+// Casbin users commonly rely on:
+//
+//	some(where (p.eft == allow))       -> allow if any matched row allows
+//	!some(where (p.eft == deny))       -> allow unless some matched row denies
+//	priority(p.eft) || deny            -> first matched row's effect wins
+func combineEffects(effect string, matched []string) (bool, error) {
+	effect = strings.TrimSpace(effect)
+	switch {
+	case strings.Contains(effect, "!some") && strings.Contains(effect, "deny"):
+		for _, e := range matched {
+			if e == "deny" {
+				return false, nil
+			}
+		}
+		return true, nil
+	case strings.Contains(effect, "priority"):
+		if len(matched) == 0 {
+			return false, nil
+		}
+		return matched[0] == "allow", nil
+	case strings.Contains(effect, "some") && strings.Contains(effect, "allow"):
+		for _, e := range matched {
+			if e == "allow" {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("policy: unsupported policy_effect %q", effect)
+	}
+}
+
+// ---- expression evaluator ----
+//
+// compileExpr parses a small boolean expression language used by matcher
+// strings. It supports &&, ||, !, ==, !=, in, parenthesized groups, string
+// literals, dotted identifiers (r.sub, p.obj, ...) and function calls
+// (keyMatch, keyMatch2, regexMatch).
+
+// expr is a compiled matcher expression.
+type expr struct {
+	root node
+}
+
+// Eval evaluates the expression against the given variable bindings.
+func (e *expr) Eval(bindings map[string]interface{}) (interface{}, error) {
+	return e.root.eval(bindings)
+}
+
+// node is a single node in the expression tree.
+type node interface {
+	eval(bindings map[string]interface{}) (interface{}, error)
+}
+
+func compileExpr(src string) (*expr, error) {
+	p := &exprParser{tokens: tokenizeExpr(src)}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q", p.tokens[p.pos].text)
+	}
+	return &expr{root: n}, nil
+}
+
+type tokKind int
+
+const (
+	tokIdent tokKind = iota
+	tokString
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokIn
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokKind
+	text string
+}
+
+func tokenizeExpr(src string) []token {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ","})
+			i++
+		case c == '"' || c == '\'':
+			quote := c
+			j := i + 1
+			for j < len(src) && src[j] != quote {
+				j++
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : j]})
+			i = j + 1
+		case c == '&' && i+1 < len(src) && src[i+1] == '&':
+			tokens = append(tokens, token{tokAnd, "&&"})
+			i += 2
+		case c == '|' && i+1 < len(src) && src[i+1] == '|':
+			tokens = append(tokens, token{tokOr, "||"})
+			i += 2
+		case c == '!' && i+1 < len(src) && src[i+1] == '=':
+			tokens = append(tokens, token{tokNeq, "!="})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, token{tokNot, "!"})
+			i++
+		case c == '=' && i+1 < len(src) && src[i+1] == '=':
+			tokens = append(tokens, token{tokEq, "=="})
+			i += 2
+		default:
+			j := i
+			for j < len(src) && isIdentRune(src[j]) {
+				j++
+			}
+			if j == i {
+				// Unknown character, skip it.
+				i++
+				continue
+			}
+			word := src[i:j]
+			if word == "in" {
+				tokens = append(tokens, token{tokIn, word})
+			} else {
+				tokens = append(tokens, token{tokIdent, word})
+			}
+			i = j
+		}
+	}
+	return tokens
+}
+
+func isIdentRune(c byte) bool {
+	return c == '.' || c == '_' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *exprParser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binOpNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokAnd {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binOpNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (node, error) {
+	if t, ok := p.peek(); ok && t.kind == tokNot {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.peek()
+	if !ok {
+		return left, nil
+	}
+	switch t.kind {
+	case tokEq, tokNeq, tokIn:
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &cmpNode{op: t.kind, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	switch t.kind {
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.next()
+		if !ok || closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected closing parenthesis")
+		}
+		return n, nil
+	case tokString:
+		return &litNode{value: t.text}, nil
+	case tokIdent:
+		if next, ok := p.peek(); ok && next.kind == tokLParen {
+			p.next()
+			var args []node
+			if closing, ok := p.peek(); !ok || closing.kind != tokRParen {
+				for {
+					arg, err := p.parseOr()
+					if err != nil {
+						return nil, err
+					}
+					args = append(args, arg)
+					comma, ok := p.peek()
+					if !ok || comma.kind != tokComma {
+						break
+					}
+					p.next()
+				}
+			}
+			closing, ok := p.next()
+			if !ok || closing.kind != tokRParen {
+				return nil, fmt.Errorf("expected closing parenthesis in call to %s", t.text)
+			}
+			return &callNode{fn: t.text, args: args}, nil
+		}
+		return &identNode{name: t.text}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %q", t.text)
+}
+
+type litNode struct{ value string }
+
+func (n *litNode) eval(map[string]interface{}) (interface{}, error) { return n.value, nil }
+
+type identNode struct{ name string }
+
+func (n *identNode) eval(bindings map[string]interface{}) (interface{}, error) {
+	if v, ok := bindings[n.name]; ok {
+		return v, nil
+	}
+	// Bare words that aren't bound (e.g. "allow", "deny" in matcher text)
+	// are treated as string literals.
+	return n.name, nil
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(bindings map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(v), nil
+}
+
+type binOpNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binOpNode) eval(bindings map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(bindings)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !truthy(l) {
+		return false, nil
+	}
+	if n.op == "||" && truthy(l) {
+		return true, nil
+	}
+	r, err := n.right.eval(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(r), nil
+}
+
+type cmpNode struct {
+	op          tokKind
+	left, right node
+}
+
+func (n *cmpNode) eval(bindings map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(bindings)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(bindings)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case tokEq:
+		return fmt.Sprintf("%v", l) == fmt.Sprintf("%v", r), nil
+	case tokNeq:
+		return fmt.Sprintf("%v", l) != fmt.Sprintf("%v", r), nil
+	case tokIn:
+		rs, ok := r.(string)
+		if !ok {
+			return false, nil
+		}
+		ls := fmt.Sprintf("%v", l)
+		for _, part := range strings.Split(rs, ",") {
+			if strings.TrimSpace(part) == ls {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported comparison operator")
+}
+
+type callNode struct {
+	fn   string
+	args []node
+}
+
+func (n *callNode) eval(bindings map[string]interface{}) (interface{}, error) {
+	args := make([]string, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(bindings)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = fmt.Sprintf("%v", v)
+	}
+	switch n.fn {
+	case "keyMatch":
+		return keyMatch(args[0], args[1]), nil
+	case "keyMatch2":
+		return keyMatch2(args[0], args[1]), nil
+	case "regexMatch":
+		return regexMatch(args[0], args[1]), nil
+	}
+	return nil, fmt.Errorf("unknown matcher function %q", n.fn)
+}
+
+// keyMatch reports whether key1 matches the "*"-wildcard pattern key2, in
+// the style of Casbin's KeyMatch: everything after the first "*" in key2 is
+// ignored.
+func keyMatch(key1, key2 string) bool {
+	idx := strings.Index(key2, "*")
+	if idx == -1 {
+		return key1 == key2
+	}
+	return strings.HasPrefix(key1, key2[:idx])
+}
+
+// keyMatch2 reports whether key1 matches key2, where key2 may contain
+// ":param" path segments (matching exactly one segment) and "/*" wildcards
+// (the "*" matching any run of characters, "/" included), in the style of
+// Casbin's KeyMatch2. A "*" not preceded by "/" is left as regex syntax.
+func keyMatch2(key1, key2 string) bool {
+	key2 = strings.ReplaceAll(key2, "/*", "/.*")
+	re := regexp.MustCompile(`:[^/]+`)
+	key2 = re.ReplaceAllString(key2, "[^/]+")
+	return regexMatch(key1, "^"+key2+"$")
+}
+
+// regexMatch reports whether key1 matches the regular expression key2.
+func regexMatch(key1, key2 string) bool {
+	matched, err := regexp.MatchString(key2, key1)
+	return err == nil && matched
+}
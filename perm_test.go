@@ -0,0 +1,80 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cvcio/policy"
+)
+
+func writeModelFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.conf")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write model file: %v", err)
+	}
+	return path
+}
+
+func TestPolicyManager_Enforce(t *testing.T) {
+	modelFile := writeModelFile(t, `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act, eft
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = keyMatch(r.sub, p.sub) && keyMatch(r.obj, p.obj) && keyMatch(r.act, p.act)
+`)
+
+	testCases := []struct {
+		name           string
+		request        []interface{}
+		expectedResult bool
+		expectErr      bool
+	}{
+		{
+			name:           "Matching subject, object and action",
+			request:        []interface{}{"admin", "*", "write"},
+			expectedResult: true,
+		},
+		{
+			name:           "No matching policy",
+			request:        []interface{}{"guest", "resource1", "write"},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pm := policy.NewPolicyManager(
+				policy.WithModel(modelFile),
+				policy.WithDefaultPolicies(),
+			)
+
+			result, err := pm.Enforce(tc.request...)
+			if tc.expectErr && err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tc.expectedResult {
+				t.Errorf("Expected %v, but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestPolicyManager_Enforce_NoModel(t *testing.T) {
+	pm := policy.NewPolicyManager(policy.WithDefaultPolicies())
+
+	if _, err := pm.Enforce("admin", "*", "write"); err == nil {
+		t.Fatal("expected an error when no model is configured")
+	}
+}
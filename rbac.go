@@ -0,0 +1,199 @@
+package policy
+
+import "sync"
+
+// RoleBinding declares that a role inherits the permissions of one or more
+// parent roles, mirroring Kubernetes RBAC's `g` role definitions
+// (e.g. "editor" inherits from "viewer").
+type RoleBinding struct {
+	// Role is the child role.
+	Role string
+	// Parents lists the roles that Role inherits from.
+	Parents []string
+}
+
+// GroupBinding declares the users that belong to a group.
+//
+// Group names share the same namespace as role names: EffectiveRoles and
+// matchPolicy expand a user's groups through the same roleParents hierarchy
+// configured by WithRoleHierarchy, with no separate group-to-role mapping
+// step. A user therefore effectively holds the "role" matching any group
+// they belong to, even with no RoleBinding for it; name groups and roles
+// the same way (or keep them identical, e.g. an "admin" group granting the
+// "admin" role) to get inheritance through PolicySpec.Role.
+type GroupBinding struct {
+	// Group is the group name.
+	Group string
+	// Users lists the members of Group.
+	Users []string
+}
+
+// WithRoleHierarchy configures the PolicyManager's RoleManager with the
+// given role and group inheritance bindings.
+func WithRoleHierarchy(bindings ...RoleBinding) PolicyOption {
+	return func(p *PolicyManager) error {
+		p.roles().addRoleBindings(bindings...)
+		return nil
+	}
+}
+
+// WithGroupBindings configures the PolicyManager's RoleManager with the
+// given group membership bindings.
+func WithGroupBindings(bindings ...GroupBinding) PolicyOption {
+	return func(p *PolicyManager) error {
+		p.roles().addGroupBindings(bindings...)
+		return nil
+	}
+}
+
+// RoleManager computes and caches the transitive closure of role and group
+// inheritance so that Evaluate can match policies against a user's
+// effective roles and groups rather than just their direct ones.
+type RoleManager struct {
+	mu sync.RWMutex
+
+	// roleParents maps a role to the roles it directly inherits from.
+	roleParents map[string][]string
+	// groupUsers maps a group to its direct members.
+	groupUsers map[string][]string
+	// userGroups maps a user to the groups it directly belongs to.
+	userGroups map[string][]string
+
+	// effectiveRoles caches the transitive closure of roleParents per role.
+	effectiveRoles map[string][]string
+}
+
+// newRoleManager returns an empty RoleManager.
+func newRoleManager() *RoleManager {
+	return &RoleManager{
+		roleParents:    map[string][]string{},
+		groupUsers:     map[string][]string{},
+		userGroups:     map[string][]string{},
+		effectiveRoles: map[string][]string{},
+	}
+}
+
+// roles returns the PolicyManager's RoleManager, set up eagerly by
+// NewPolicyManagerWithError so concurrent Evaluate calls never race on its
+// initialization.
+func (p *PolicyManager) roles() *RoleManager {
+	return p.roleManager
+}
+
+// addRoleBindings merges role inheritance bindings and invalidates the
+// cached transitive closure.
+func (rm *RoleManager) addRoleBindings(bindings ...RoleBinding) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	for _, b := range bindings {
+		rm.roleParents[b.Role] = append(rm.roleParents[b.Role], b.Parents...)
+	}
+	rm.invalidate()
+}
+
+// addGroupBindings merges group membership bindings and invalidates the
+// cached transitive closure.
+func (rm *RoleManager) addGroupBindings(bindings ...GroupBinding) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	for _, b := range bindings {
+		rm.groupUsers[b.Group] = append(rm.groupUsers[b.Group], b.Users...)
+		for _, u := range b.Users {
+			rm.userGroups[u] = append(rm.userGroups[u], b.Group)
+		}
+	}
+	rm.invalidate()
+}
+
+// invalidate clears the cached transitive closure. Callers must hold rm.mu.
+func (rm *RoleManager) invalidate() {
+	rm.effectiveRoles = map[string][]string{}
+}
+
+// effectiveRolesFor returns the transitive closure of role, i.e. role
+// itself plus every role it inherits from directly or indirectly. Cycles
+// are broken via a visited set.
+func (rm *RoleManager) effectiveRolesFor(role string) []string {
+	rm.mu.RLock()
+	if cached, ok := rm.effectiveRoles[role]; ok {
+		rm.mu.RUnlock()
+		return cached
+	}
+	rm.mu.RUnlock()
+
+	visited := map[string]bool{}
+	var walk func(r string)
+	walk = func(r string) {
+		if visited[r] {
+			return
+		}
+		visited[r] = true
+		for _, parent := range rm.roleParents[r] {
+			walk(parent)
+		}
+	}
+	walk(role)
+
+	result := make([]string, 0, len(visited))
+	for r := range visited {
+		result = append(result, r)
+	}
+
+	rm.mu.Lock()
+	rm.effectiveRoles[role] = result
+	rm.mu.Unlock()
+
+	return result
+}
+
+// groupsFor returns the groups a user directly belongs to. It returns a
+// defensive copy: callers such as matchPolicy append a request-scoped
+// GroupID to the result, and returning the RoleManager's own backing slice
+// would let that append mutate userGroups in place whenever it had spare
+// capacity.
+func (rm *RoleManager) groupsFor(user string) []string {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	groups := rm.userGroups[user]
+	return append([]string(nil), groups...)
+}
+
+// EffectiveRoles returns every role user has been granted via group
+// membership, expanded through role inheritance configured with
+// WithRoleHierarchy. It does not include roles asserted directly on a
+// request's UserAttributes; Evaluate combines both when matching policies.
+// See GroupBinding for the coupling this relies on: a user's group names
+// are looked up directly in the role hierarchy, as if they were role names.
+func (p *PolicyManager) EffectiveRoles(user string) []string {
+	rm := p.roles()
+	return p.expandRoles(rm.groupsFor(user))
+}
+
+// HasRole reports whether role is among user's effective roles, i.e. a
+// group user belongs to, or a role any of those groups transitively
+// inherits from.
+func (p *PolicyManager) HasRole(user, role string) bool {
+	for _, r := range p.EffectiveRoles(user) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// expandRoles returns the transitive closure of the given roles, de-duped,
+// via role inheritance configured with WithRoleHierarchy.
+func (p *PolicyManager) expandRoles(roles []string) []string {
+	rm := p.roles()
+	seen := map[string]bool{}
+	var result []string
+	for _, r := range roles {
+		for _, effective := range rm.effectiveRolesFor(r) {
+			if !seen[effective] {
+				seen[effective] = true
+				result = append(result, effective)
+			}
+		}
+	}
+	return result
+}
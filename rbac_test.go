@@ -0,0 +1,154 @@
+package policy_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cvcio/policy"
+)
+
+func TestPolicyManager_EffectiveRoles(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithRoleHierarchy(
+			policy.RoleBinding{Role: "editor", Parents: []string{"viewer"}},
+			policy.RoleBinding{Role: "admin", Parents: []string{"editor"}},
+		),
+		policy.WithGroupBindings(
+			policy.GroupBinding{Group: "admin", Users: []string{"alice"}},
+		),
+	)
+
+	roles := pm.EffectiveRoles("alice")
+	want := map[string]bool{"admin": true, "editor": true, "viewer": true}
+	if len(roles) != len(want) {
+		t.Fatalf("expected %d effective roles, got %v", len(want), roles)
+	}
+	for _, r := range roles {
+		if !want[r] {
+			t.Errorf("unexpected effective role %q", r)
+		}
+	}
+
+	if !pm.HasRole("alice", "viewer") {
+		t.Error("expected alice to transitively have the viewer role")
+	}
+	if pm.HasRole("bob", "viewer") {
+		t.Error("did not expect bob to have any role")
+	}
+}
+
+func TestPolicyManager_Evaluate_RoleHierarchy(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithRoleHierarchy(
+			policy.RoleBinding{Role: "admin", Parents: []string{"editor"}},
+		),
+		policy.WithGroupBindings(
+			policy.GroupBinding{Group: "admin", Users: []string{"alice"}},
+		),
+		policy.WithPolicies(policy.PolicySpec{
+			Role:     "editor",
+			Resource: "documents",
+			ReadOnly: true,
+		}),
+	)
+
+	result := pm.Evaluate(
+		&policy.UserAttributes{UserID: "alice"},
+		&policy.ResourceAttributes{Resource: "documents", ReadOnly: true},
+	)
+	if !result {
+		t.Error("expected alice to inherit the editor policy through the admin group")
+	}
+}
+
+func TestPolicyManager_Evaluate_GroupIDIsRequestScoped(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithGroupBindings(
+			policy.GroupBinding{Group: "team-a", Users: []string{"alice"}},
+			policy.GroupBinding{Group: "team-b", Users: []string{"alice"}},
+			policy.GroupBinding{Group: "team-c", Users: []string{"alice"}},
+		),
+		policy.WithPolicies(policy.PolicySpec{
+			Role:     "team-z",
+			Resource: "documents",
+			ReadOnly: true,
+		}),
+	)
+
+	// A request asserting an unrelated, transient GroupID must not corrupt
+	// alice's persisted group membership for later, unrelated requests.
+	pm.Evaluate(
+		&policy.UserAttributes{UserID: "alice", GroupID: "team-z"},
+		&policy.ResourceAttributes{Resource: "documents", ReadOnly: true},
+	)
+
+	roles := pm.EffectiveRoles("alice")
+	want := map[string]bool{"team-a": true, "team-b": true, "team-c": true}
+	if len(roles) != len(want) {
+		t.Fatalf("expected %d effective roles, got %v", len(want), roles)
+	}
+	for _, r := range roles {
+		if !want[r] {
+			t.Errorf("unexpected effective role %q leaked from a transient GroupID", r)
+		}
+	}
+}
+
+// TestPolicyManager_Evaluate_ConcurrentGroupIDIsRaceFree fires concurrent
+// Evaluate calls carrying distinct, transient GroupIDs for a user with
+// several persisted groups (enough for the groups slice to have spare
+// capacity). Without groupsFor returning a defensive copy, these calls
+// append into the RoleManager's shared backing array without holding its
+// lock; run with `go test -race` this reliably reports a data race.
+func TestPolicyManager_Evaluate_ConcurrentGroupIDIsRaceFree(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithGroupBindings(
+			policy.GroupBinding{Group: "team-a", Users: []string{"alice"}},
+			policy.GroupBinding{Group: "team-b", Users: []string{"alice"}},
+			policy.GroupBinding{Group: "team-c", Users: []string{"alice"}},
+		),
+		policy.WithPolicies(policy.PolicySpec{
+			Role:     "*",
+			Resource: "documents",
+			ReadOnly: true,
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pm.Evaluate(
+				&policy.UserAttributes{UserID: "alice", GroupID: "transient"},
+				&policy.ResourceAttributes{Resource: "documents", ReadOnly: true},
+			)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestPolicyManager_Evaluate_ConcurrentOnFreshManager fires concurrent
+// Evaluate calls on a manager that never called WithRoleHierarchy or
+// WithGroupBindings. Before roleManager was initialized eagerly by
+// NewPolicyManagerWithError, roles() lazily assigned it on first use with
+// no synchronization, and this reliably reported a data race under
+// `go test -race`.
+func TestPolicyManager_Evaluate_ConcurrentOnFreshManager(t *testing.T) {
+	pm := policy.NewPolicyManager(
+		policy.WithPolicies(policy.PolicySpec{Role: "*", Resource: "documents", ReadOnly: true}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pm.Evaluate(
+				&policy.UserAttributes{UserID: "alice"},
+				&policy.ResourceAttributes{Resource: "documents", ReadOnly: true},
+			)
+		}()
+	}
+	wg.Wait()
+}